@@ -0,0 +1,121 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ensureUsersTable creates the users table backing login if it doesn't
+// already exist.
+func ensureUsersTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS users (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			username TEXT NOT NULL UNIQUE,
+			password_hash TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// CreateUser hashes password and inserts a new row into users, for
+// provisioning accounts out of band (e.g. a setup CLI command).
+func CreateUser(db *sql.DB, username, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %v", err)
+	}
+	_, err = db.Exec("INSERT INTO users (username, password_hash) VALUES (?, ?)", username, string(hash))
+	return err
+}
+
+// sessionClaims is the JWT payload issued on successful login.
+type sessionClaims struct {
+	Username string `json:"username"`
+	jwt.RegisteredClaims
+}
+
+// issueToken signs a JWT for username valid for 24 hours.
+func (s *Server) issueToken(username string) (string, error) {
+	claims := sessionClaims{
+		Username: username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.jwtSecret)
+}
+
+// verifyToken checks tokenString's signature and expiry, returning the
+// username it was issued for.
+func (s *Server) verifyToken(tokenString string) (string, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &sessionClaims{}, func(t *jwt.Token) (interface{}, error) {
+		return s.jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return "", errors.New("invalid token")
+	}
+	claims, ok := token.Claims.(*sessionClaims)
+	if !ok {
+		return "", errors.New("invalid token claims")
+	}
+	return claims.Username, nil
+}
+
+// requireAuth is middleware guarding write endpoints: it expects a
+// "Authorization: Bearer <token>" header and rejects the request with 401 if
+// the token is missing or invalid.
+func (s *Server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if tokenString == "" {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+		if _, err := s.verifyToken(tokenString); err != nil {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleLogin serves POST /api/login, issuing a JWT for valid credentials.
+func handleLogin(s *Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var creds struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		var hash string
+		err := s.db.QueryRow("SELECT password_hash FROM users WHERE username = ?", creds.Username).Scan(&hash)
+		if err != nil || bcrypt.CompareHashAndPassword([]byte(hash), []byte(creds.Password)) != nil {
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+
+		token, err := s.issueToken(creds.Username)
+		if err != nil {
+			http.Error(w, "error issuing token", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{"token": token})
+	}
+}
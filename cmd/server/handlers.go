@@ -0,0 +1,152 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/develpudu/bookmark-parser/parser"
+)
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// handleListBookmarks serves GET /api/bookmarks, supporting the same "q",
+// "tag", and "folder" filters as the search subcommand.
+func (s *Server) handleListBookmarks(w http.ResponseWriter, r *http.Request) {
+	results, err := parser.SearchBookmarks(s.db, r.URL.Query().Get("q"), parser.SearchOptions{
+		Tag:        r.URL.Query().Get("tag"),
+		Folder:     r.URL.Query().Get("folder"),
+		ExcludeTag: r.URL.Query().Get("exclude-tag"),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// handleCreateBookmark serves POST /api/bookmarks.
+func (s *Server) handleCreateBookmark(w http.ResponseWriter, r *http.Request) {
+	var b parser.Bookmark
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := parser.SaveBookmarks(s.db, []parser.Bookmark{b}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, b)
+}
+
+// handleUpdateBookmark serves PUT /api/bookmarks/{id}.
+func (s *Server) handleUpdateBookmark(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var b parser.Bookmark
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	_, err = s.db.Exec(
+		"UPDATE bookmarks SET title = ?, url = ?, folder = ?, tags = ? WHERE id = ?",
+		b.Title, b.URL, b.Folder, strings.Join(b.Tags, ","), id,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	b.ID = id
+	writeJSON(w, http.StatusOK, b)
+}
+
+// handleDeleteBookmark serves DELETE /api/bookmarks/{id}.
+func (s *Server) handleDeleteBookmark(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if _, err := s.db.Exec("DELETE FROM bookmarks WHERE id = ?", id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleValidate serves POST /api/validate, triggering a validation pass
+// over every bookmark. It goes through the Store-backed validator (rate
+// limiting, per-host concurrency caps, resumability) rather than the old
+// whole-table-transaction validator, which would otherwise lock the database
+// for the duration of the run on every request.
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	store := parser.NewSQLiteStore(s.db)
+	if err := parser.ValidateAndUpdateStore(store, parser.ValidateOptions{}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "validated"})
+}
+
+// handleExport serves GET /api/export?type=valid|dead|redirect, streaming a
+// Chrome-compatible HTML export of the matching bookmarks.
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	tmp, err := os.CreateTemp("", "bookmarks-export-*.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	switch r.URL.Query().Get("type") {
+	case "dead":
+		err = parser.ExportDeadBookmarks(s.db, tmp.Name())
+	case "redirect":
+		err = parser.ExportRedirectBookmarks(s.db, tmp.Name())
+	default:
+		err = parser.ExportValidBookmarks(s.db, tmp.Name())
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	http.ServeFile(w, r, tmp.Name())
+}
+
+// handleArchive serves GET /api/archive/{id}, returning the
+// readability-cleaned HTML for an already-archived bookmark.
+func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var archivePath string
+	var hasArchive bool
+	err = s.db.QueryRow("SELECT archive_path, has_archive FROM bookmarks WHERE id = ?", id).
+		Scan(&archivePath, &hasArchive)
+	if err != nil || !hasArchive {
+		http.Error(w, "no archive for bookmark", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	http.ServeFile(w, r, filepath.Join(archivePath, "readable.html"))
+}
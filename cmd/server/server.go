@@ -0,0 +1,52 @@
+// Package server exposes the bookmark-parser REST API and a small embedded
+// web UI over an existing bookmarks database.
+package server
+
+import (
+	"database/sql"
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var staticFS embed.FS
+
+// Server wires the REST API and embedded web UI on top of an existing
+// bookmarks database.
+type Server struct {
+	db        *sql.DB
+	jwtSecret []byte
+}
+
+// New returns a Server backed by db, signing JWTs with jwtSecret. It ensures
+// the users table used for authentication exists.
+func New(db *sql.DB, jwtSecret []byte) (*Server, error) {
+	if err := ensureUsersTable(db); err != nil {
+		return nil, err
+	}
+	return &Server{db: db, jwtSecret: jwtSecret}, nil
+}
+
+// Router builds the HTTP handler for the REST API and web UI. Write endpoints
+// are guarded by requireAuth; everything else is public.
+func (s *Server) Router() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /api/login", handleLogin(s))
+	mux.HandleFunc("GET /api/bookmarks", s.handleListBookmarks)
+	mux.HandleFunc("POST /api/bookmarks", s.requireAuth(s.handleCreateBookmark))
+	mux.HandleFunc("PUT /api/bookmarks/{id}", s.requireAuth(s.handleUpdateBookmark))
+	mux.HandleFunc("DELETE /api/bookmarks/{id}", s.requireAuth(s.handleDeleteBookmark))
+	mux.HandleFunc("POST /api/validate", s.requireAuth(s.handleValidate))
+	mux.HandleFunc("GET /api/export", s.handleExport)
+	mux.HandleFunc("GET /api/archive/{id}", s.handleArchive)
+
+	assets, err := fs.Sub(staticFS, "static")
+	if err != nil {
+		panic(err)
+	}
+	mux.Handle("/", http.FileServer(http.FS(assets)))
+
+	return mux
+}
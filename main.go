@@ -5,8 +5,12 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/develpudu/bookmark-parser/cmd/server"
 	"github.com/develpudu/bookmark-parser/parser"
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -26,6 +30,46 @@ func ensureDataDir() error {
 	return nil
 }
 
+// openStore opens the parser.Store selected by driver/dsn, falling back to
+// the BM_DB_DRIVER/BM_DB_DSN environment variables and finally the local
+// SQLite file used by the rest of the CLI.
+func openStore(driver, dsn string) (parser.Store, error) {
+	if driver == "" {
+		driver = os.Getenv("BM_DB_DRIVER")
+	}
+	if dsn == "" {
+		dsn = os.Getenv("BM_DB_DSN")
+	}
+	if dsn == "" {
+		dsn = dbPath
+	}
+	return parser.NewStore(driver, dsn)
+}
+
+// openSQLiteDB opens the raw *sql.DB used by the archive and serve
+// subcommands. ArchiveBookmark, the EPUB generator, and cmd/server predate
+// the parser.Store abstraction and still talk to SQLite directly, so unlike
+// openStore this resolves the same driver/dsn precedence (flag, then
+// BM_DB_DRIVER/BM_DB_DSN, then the local SQLite file) but fails fast if a
+// non-SQLite driver is requested instead of silently ignoring it.
+func openSQLiteDB(driver, dsn string) (*sql.DB, error) {
+	if driver == "" {
+		driver = os.Getenv("BM_DB_DRIVER")
+	}
+	if dsn == "" {
+		dsn = os.Getenv("BM_DB_DSN")
+	}
+	if dsn == "" {
+		dsn = dbPath
+	}
+	switch driver {
+	case "", "sqlite", "sqlite3":
+	default:
+		return nil, fmt.Errorf("archive and serve only support the sqlite driver for now, got %q", driver)
+	}
+	return sql.Open("sqlite3", dsn)
+}
+
 func main() {
 	// Ensure data directory exists
 	if err := ensureDataDir(); err != nil {
@@ -34,28 +78,50 @@ func main() {
 
 	// Define subcommands
 	parseCmd := flag.NewFlagSet("parse", flag.ExitOnError)
-	bookmarkFile := parseCmd.String("file", "", "Path to the Chrome bookmarks HTML file")
+	bookmarkFile := parseCmd.String("file", "", "Path to the bookmarks export file")
+	importFormat := parseCmd.String("format", "chrome", "Import format: chrome, firefox, safari, or pocket")
+	parseDBDriver := parseCmd.String("db-driver", "", "Database driver: sqlite, mysql, or postgres (default: BM_DB_DRIVER, then sqlite)")
+	parseDBDSN := parseCmd.String("db", "", "Database connection string (default: BM_DB_DSN, then data/bookmarks.db)")
 
 	searchCmd := flag.NewFlagSet("search", flag.ExitOnError)
-	query := searchCmd.String("query", "", "Search query for bookmarks")
+	query := searchCmd.String("query", "", "Search query for bookmarks (supports FTS5 syntax: \"phrase\", prefix*, AND/OR/NOT)")
+	searchTag := searchCmd.String("tag", "", "Only show bookmarks with this tag")
+	searchFolder := searchCmd.String("folder", "", "Only show bookmarks in this folder")
+	searchExcludeTag := searchCmd.String("exclude-tag", "", "Hide bookmarks with this tag")
+	searchDBDriver := searchCmd.String("db-driver", "", "Database driver: sqlite, mysql, or postgres (default: BM_DB_DRIVER, then sqlite)")
+	searchDBDSN := searchCmd.String("db", "", "Database connection string (default: BM_DB_DSN, then data/bookmarks.db)")
 
 	validateCmd := flag.NewFlagSet("validate", flag.ExitOnError)
+	validateDBDriver := validateCmd.String("db-driver", "", "Database driver: sqlite, mysql, or postgres (default: BM_DB_DRIVER, then sqlite)")
+	validateDBDSN := validateCmd.String("db", "", "Database connection string (default: BM_DB_DSN, then data/bookmarks.db)")
+	validateResume := validateCmd.Bool("resume", false, "Only re-check bookmarks last checked more than -max-age ago")
+	validateMaxAge := validateCmd.Duration("max-age", 24*time.Hour, "With -resume, how old a bookmark's last check must be before it's re-checked")
+	validateTimeout := validateCmd.Duration("timeout", 0, "Overall time budget for the validation run (0 means no limit)")
 
 	exportCmd := flag.NewFlagSet("export", flag.ExitOnError)
 	outputFile := exportCmd.String("output", "", "Path to save the exported bookmarks HTML file")
+	exportFilter := exportCmd.String("type", "valid", "Which bookmarks to export: valid, dead, or redirect")
+	exportDBDriver := exportCmd.String("db-driver", "", "Database driver: sqlite, mysql, or postgres (default: BM_DB_DRIVER, then sqlite)")
+	exportDBDSN := exportCmd.String("db", "", "Database connection string (default: BM_DB_DSN, then data/bookmarks.db)")
+
+	archiveCmd := flag.NewFlagSet("archive", flag.ExitOnError)
+	archiveDir := archiveCmd.String("dir", "archive", "Directory to store archived bookmark content")
+	ebook := archiveCmd.Bool("ebook", false, "Additionally emit an EPUB built from the archived content")
+	ebookOutput := archiveCmd.String("ebook-output", "bookmarks.epub", "Path to write the generated EPUB")
+	archiveDBDSN := archiveCmd.String("db", "", "SQLite database path (default: BM_DB_DSN, then data/bookmarks.db; archive only supports sqlite)")
+	archiveDBDriver := archiveCmd.String("db-driver", "", "Database driver: sqlite only for now (default: BM_DB_DRIVER, then sqlite)")
+
+	serveCmd := flag.NewFlagSet("serve", flag.ExitOnError)
+	serveAddr := serveCmd.String("addr", ":8080", "Address to listen on")
+	jwtSecret := serveCmd.String("jwt-secret", "", "Secret used to sign session JWTs (required)")
+	serveDBDSN := serveCmd.String("db", "", "SQLite database path (default: BM_DB_DSN, then data/bookmarks.db; serve only supports sqlite)")
+	serveDBDriver := serveCmd.String("db-driver", "", "Database driver: sqlite only for now (default: BM_DB_DRIVER, then sqlite)")
 
 	if len(os.Args) < 2 {
-		fmt.Println("expected 'parse', 'search', 'validate', or 'export' subcommands")
+		fmt.Println("expected 'parse', 'search', 'validate', 'export', 'archive', or 'serve' subcommands")
 		os.Exit(1)
 	}
 
-	// Initialize database
-	db, err := sql.Open("sqlite3", dbPath)
-	if err != nil {
-		log.Fatalf("Error opening database: %v", err)
-	}
-	defer db.Close()
-
 	// Handle subcommands
 	switch os.Args[1] {
 	case "parse":
@@ -63,23 +129,48 @@ func main() {
 		if *bookmarkFile == "" {
 			log.Fatal("Please provide a bookmark file path using -file flag")
 		}
-		if err := parseBookmarks(db, *bookmarkFile); err != nil {
+		store, err := openStore(*parseDBDriver, *parseDBDSN)
+		if err != nil {
+			log.Fatalf("Error opening database: %v", err)
+		}
+		defer store.Close()
+		if err := parseBookmarks(store, *bookmarkFile, *importFormat); err != nil {
 			log.Fatalf("Error parsing bookmarks: %v", err)
 		}
 
 	case "search":
 		searchCmd.Parse(os.Args[2:])
-		if *query == "" {
-			log.Fatal("Please provide a search query using -query flag")
+		if *query == "" && *searchTag == "" && *searchFolder == "" {
+			log.Fatal("Please provide a search query using -query, -tag, or -folder")
+		}
+		store, err := openStore(*searchDBDriver, *searchDBDSN)
+		if err != nil {
+			log.Fatalf("Error opening database: %v", err)
+		}
+		defer store.Close()
+		opts := parser.SearchOptions{
+			Tag:        *searchTag,
+			Folder:     *searchFolder,
+			ExcludeTag: *searchExcludeTag,
 		}
-		if err := searchBookmarks(db, *query); err != nil {
+		if err := searchBookmarks(store, *query, opts); err != nil {
 			log.Fatalf("Error searching bookmarks: %v", err)
 		}
 
 	case "validate":
 		validateCmd.Parse(os.Args[2:])
+		store, err := openStore(*validateDBDriver, *validateDBDSN)
+		if err != nil {
+			log.Fatalf("Error opening database: %v", err)
+		}
+		defer store.Close()
 		fmt.Println("Validating bookmarks...")
-		if err := parser.ValidateAndUpdateBookmarks(db); err != nil {
+		opts := parser.ValidateOptions{
+			Resume:  *validateResume,
+			MaxAge:  *validateMaxAge,
+			Timeout: *validateTimeout,
+		}
+		if err := parser.ValidateAndUpdateStore(store, opts); err != nil {
 			log.Fatalf("Error validating bookmarks: %v", err)
 		}
 		fmt.Println("Validation complete")
@@ -89,32 +180,74 @@ func main() {
 		if *outputFile == "" {
 			log.Fatal("Please provide an output file path using -output flag")
 		}
-		if err := parser.ExportValidBookmarks(db, *outputFile); err != nil {
+		store, err := openStore(*exportDBDriver, *exportDBDSN)
+		if err != nil {
+			log.Fatalf("Error opening database: %v", err)
+		}
+		defer store.Close()
+		if err := exportBookmarks(store, *exportFilter, *outputFile); err != nil {
 			log.Fatalf("Error exporting bookmarks: %v", err)
 		}
-		fmt.Printf("Successfully exported valid bookmarks to %s\n", *outputFile)
+		fmt.Printf("Successfully exported %s bookmarks to %s\n", *exportFilter, *outputFile)
+
+	case "archive":
+		archiveCmd.Parse(os.Args[2:])
+		db, err := openSQLiteDB(*archiveDBDriver, *archiveDBDSN)
+		if err != nil {
+			log.Fatalf("Error opening database: %v", err)
+		}
+		defer db.Close()
+		if err := archiveBookmarks(db, *archiveDir, *ebook, *ebookOutput); err != nil {
+			log.Fatalf("Error archiving bookmarks: %v", err)
+		}
+
+	case "serve":
+		serveCmd.Parse(os.Args[2:])
+		if *jwtSecret == "" {
+			log.Fatal("Please provide a JWT signing secret using -jwt-secret flag")
+		}
+		db, err := openSQLiteDB(*serveDBDriver, *serveDBDSN)
+		if err != nil {
+			log.Fatalf("Error opening database: %v", err)
+		}
+		defer db.Close()
+		if err := parser.InitDB(db); err != nil {
+			log.Fatalf("Error initializing database: %v", err)
+		}
+		srv, err := server.New(db, []byte(*jwtSecret))
+		if err != nil {
+			log.Fatalf("Error starting server: %v", err)
+		}
+		fmt.Printf("Listening on %s\n", *serveAddr)
+		if err := http.ListenAndServe(*serveAddr, srv.Router()); err != nil {
+			log.Fatalf("Error serving: %v", err)
+		}
 
 	default:
-		fmt.Println("expected 'parse', 'search', 'validate', or 'export' subcommands")
+		fmt.Println("expected 'parse', 'search', 'validate', 'export', 'archive', or 'serve' subcommands")
 		os.Exit(1)
 	}
 
 }
 
-func parseBookmarks(db *sql.DB, filePath string) error {
+func parseBookmarks(store parser.Store, filePath, format string) error {
 	// Initialize database schema
-	if err := parser.InitDB(db); err != nil {
+	if err := store.Init(); err != nil {
 		return fmt.Errorf("error initializing database: %v", err)
 	}
 
-	// Parse bookmarks from file
-	bookmarks, err := parser.ParseFile(filePath)
+	// Parse bookmarks using the import source for the requested format
+	source, err := parser.NewImportSource(format)
+	if err != nil {
+		return fmt.Errorf("error selecting import format: %v", err)
+	}
+	bookmarks, err := source.Import(filePath)
 	if err != nil {
 		return fmt.Errorf("error parsing bookmarks file: %v", err)
 	}
 
 	// Save bookmarks to database
-	if err := parser.SaveBookmarks(db, bookmarks); err != nil {
+	if err := store.Save(bookmarks); err != nil {
 		return fmt.Errorf("error saving bookmarks: %v", err)
 	}
 
@@ -122,37 +255,81 @@ func parseBookmarks(db *sql.DB, filePath string) error {
 	return nil
 }
 
-func searchBookmarks(db *sql.DB, query string) error {
-	rows, err := db.Query(`
-		SELECT title, url, folder, is_dead, is_redirect
-		FROM bookmarks
-		WHERE title LIKE ? OR url LIKE ?
-	`, "%"+query+"%", "%"+query+"%")
+func searchBookmarks(store parser.Store, query string, opts parser.SearchOptions) error {
+	results, err := store.Search(query, opts)
 	if err != nil {
 		return fmt.Errorf("error searching bookmarks: %v", err)
 	}
-	defer rows.Close()
 
-	var found bool
-	for rows.Next() {
-		var b parser.Bookmark
-		err := rows.Scan(&b.Title, &b.URL, &b.Folder, &b.Dead, &b.Redirect)
-		if err != nil {
-			return fmt.Errorf("error scanning bookmark: %v", err)
-		}
+	if len(results) == 0 {
+		fmt.Println("No bookmarks found matching your query")
+		return nil
+	}
 
-		found = true
-		fmt.Printf("\nTitle: %s\nURL: %s\nFolder: %s\n", b.Title, b.URL, b.Folder)
-		if b.Dead {
+	for _, r := range results {
+		fmt.Printf("\nTitle: %s\nURL: %s\nFolder: %s\n", r.Title, r.URL, r.Folder)
+		if len(r.Tags) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(r.Tags, ", "))
+		}
+		if r.Snippet != "" {
+			fmt.Printf("Match: %s\n", r.Snippet)
+		}
+		if r.Dead {
 			fmt.Println("Status: Dead link")
 		}
-		if b.Redirect {
+		if r.Redirect {
 			fmt.Println("Status: Redirects to another location")
 		}
 	}
 
-	if !found {
-		fmt.Println("No bookmarks found matching your query")
+	return nil
+}
+
+// exportBookmarks writes the bookmarks matching filter ("valid", "dead", or
+// "redirect") to a Chrome-compatible HTML file at outputPath.
+func exportBookmarks(store parser.Store, filter, outputPath string) error {
+	bookmarks, err := store.List(filter)
+	if err != nil {
+		return fmt.Errorf("error listing bookmarks: %v", err)
+	}
+	return parser.WriteBookmarksHTML(bookmarks, outputPath, fmt.Sprintf("%s Bookmarks", filter))
+}
+
+// archiveBookmarks archives every non-dead bookmark into storageDir, then
+// optionally bundles the archived content into an EPUB.
+func archiveBookmarks(db *sql.DB, storageDir string, ebook bool, ebookOutput string) error {
+	rows, err := db.Query("SELECT id FROM bookmarks WHERE is_dead = FALSE")
+	if err != nil {
+		return fmt.Errorf("error listing bookmarks: %v", err)
+	}
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning bookmark id: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := parser.ArchiveBookmark(db, id, storageDir); err != nil {
+			fmt.Printf("Warning: failed to archive bookmark %d: %v\n", id, err)
+			continue
+		}
+		fmt.Printf("Archived bookmark %d\n", id)
+	}
+
+	if ebook {
+		if err := parser.GenerateEPUB(db, ids, storageDir, ebookOutput); err != nil {
+			return fmt.Errorf("error generating epub: %v", err)
+		}
+		fmt.Printf("Successfully generated ebook at %s\n", ebookOutput)
 	}
 
 	return nil
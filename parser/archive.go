@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// ArchiveBookmark fetches the bookmark identified by id, extracts its main
+// article content with a readability pass, and persists three artifacts
+// under storageDir/<id>/: the raw HTML (raw.html), a readability-cleaned HTML
+// file (readable.html), and a WARC record of the HTTP exchange
+// (response.warc). The bookmark's excerpt, content_text, archive_path, and
+// has_archive columns are updated to reflect the archive.
+func ArchiveBookmark(db *sql.DB, id int64, storageDir string) error {
+	var url string
+	if err := db.QueryRow("SELECT url FROM bookmarks WHERE id = ?", id).Scan(&url); err != nil {
+		return fmt.Errorf("error loading bookmark %d: %v", id, err)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("error building request: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error fetching %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %v", err)
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error parsing fetched HTML: %v", err)
+	}
+
+	readable := extractReadableContent(doc)
+	cleanedText := textContent(readable)
+
+	var cleanedHTML bytes.Buffer
+	if err := html.Render(&cleanedHTML, readable); err != nil {
+		return fmt.Errorf("error rendering cleaned HTML: %v", err)
+	}
+
+	bookmarkDir := filepath.Join(storageDir, strconv.FormatInt(id, 10))
+	if err := os.MkdirAll(bookmarkDir, 0755); err != nil {
+		return fmt.Errorf("error creating archive directory: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(bookmarkDir, "raw.html"), body, 0644); err != nil {
+		return fmt.Errorf("error writing raw HTML: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(bookmarkDir, "readable.html"), cleanedHTML.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error writing readable HTML: %v", err)
+	}
+	if err := writeWARCRecord(filepath.Join(bookmarkDir, "response.warc"), req, resp, body); err != nil {
+		return fmt.Errorf("error writing WARC record: %v", err)
+	}
+
+	excerpt := cleanedText
+	if len(excerpt) > 300 {
+		excerpt = excerpt[:300]
+	}
+
+	_, err = db.Exec(`
+		UPDATE bookmarks
+		SET excerpt = ?, content_text = ?, archive_path = ?, has_archive = TRUE
+		WHERE id = ?
+	`, excerpt, cleanedText, bookmarkDir, id)
+	if err != nil {
+		return fmt.Errorf("error recording archive metadata: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+var trackingParamPrefixes = []string{"utm_"}
+
+var trackingParamNames = map[string]bool{
+	"fbclid": true,
+	"gclid":  true,
+	"mc_cid": true,
+}
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// CanonicalizeURL normalizes u for duplicate matching: it lowercases the
+// scheme and host, strips a leading "www." and default ports from the
+// host, removes the fragment, drops known tracking query parameters
+// (utm_*, fbclid, gclid, mc_cid), sorts the remaining query keys, and
+// normalizes percent-encoding and trailing slashes in the path (treating a
+// missing path the same as "/"). URLs that fail to parse are returned
+// unchanged so callers can still fall back to exact-match comparison.
+func CanonicalizeURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	scheme := strings.ToLower(parsed.Scheme)
+	parsed.Scheme = scheme
+
+	hostname := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	if port := parsed.Port(); port != "" && port != defaultPorts[scheme] {
+		parsed.Host = hostname + ":" + port
+	} else {
+		parsed.Host = hostname
+	}
+	parsed.Fragment = ""
+
+	if parsed.RawQuery != "" {
+		query := parsed.Query()
+		for key := range query {
+			lower := strings.ToLower(key)
+			if trackingParamNames[lower] || hasTrackingPrefix(lower) {
+				query.Del(key)
+			}
+		}
+		parsed.RawQuery = sortedQuery(query)
+	}
+
+	parsed.Path = canonicalizePath(parsed.Path)
+
+	return parsed.String()
+}
+
+func hasTrackingPrefix(key string) bool {
+	for _, prefix := range trackingParamPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalizePath decodes percent-escapes, collapses "." and ".." segments,
+// and drops a trailing slash (other than the root path itself). An empty
+// path is treated the same as "/", so "http://example.com" and
+// "http://example.com/" canonicalize identically.
+func canonicalizePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	decoded, err := url.PathUnescape(p)
+	if err != nil {
+		decoded = p
+	}
+
+	cleaned := path.Clean(decoded)
+	if cleaned == "." {
+		return "/"
+	}
+	if cleaned != "/" {
+		cleaned = strings.TrimSuffix(cleaned, "/")
+	}
+	return cleaned
+}
+
+// sortedQuery re-encodes query with keys in sorted order, so equivalent
+// query strings with different parameter ordering canonicalize identically.
+func sortedQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, key := range keys {
+		for j, v := range query[key] {
+			if i > 0 || j > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(key))
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(v))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package parser
+
+import "testing"
+
+func TestCanonicalizeURL(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b string
+	}{
+		{"scheme and host case", "HTTPS://Example.com/Path", "https://example.com/Path"},
+		{"default https port", "https://example.com:443/path", "https://example.com/path"},
+		{"default http port", "http://example.com:80/path", "http://example.com/path"},
+		{"www prefix", "https://www.example.com/x", "https://example.com/x"},
+		{"missing vs trailing slash path", "http://example.com", "http://example.com/"},
+		{"fragment", "https://example.com/path#section", "https://example.com/path"},
+		{"utm params", "https://example.com/?utm_source=x&id=1", "https://example.com/?id=1"},
+		{"known tracking params", "https://example.com/?fbclid=a&gclid=b&mc_cid=c&id=1", "https://example.com/?id=1"},
+		{"query key order", "https://example.com/?b=2&a=1", "https://example.com/?a=1&b=2"},
+		{"percent-encoded path", "https://example.com/%7Euser/", "https://example.com/~user"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotA := CanonicalizeURL(tc.a)
+			gotB := CanonicalizeURL(tc.b)
+			if gotA != gotB {
+				t.Errorf("CanonicalizeURL(%q) = %q, CanonicalizeURL(%q) = %q, want equal", tc.a, gotA, tc.b, gotB)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeURLInvalid(t *testing.T) {
+	const invalid = "://not a url"
+	if got := CanonicalizeURL(invalid); got != invalid {
+		t.Errorf("CanonicalizeURL(%q) = %q, want unchanged", invalid, got)
+	}
+}
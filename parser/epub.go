@@ -0,0 +1,218 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// epubChapter is one bookmark rendered as a chapter.
+type epubChapter struct {
+	id    int64
+	title string
+	html  string
+}
+
+// GenerateEPUB bundles the readability-cleaned HTML of each archived bookmark
+// in ids into a single EPUB at outputPath, one chapter per bookmark.
+// Bookmarks without an archive (has_archive = FALSE) are skipped.
+func GenerateEPUB(db *sql.DB, ids []int64, storageDir, outputPath string) error {
+	var chapters []epubChapter
+	for _, id := range ids {
+		var title, archivePath string
+		var hasArchive bool
+		err := db.QueryRow("SELECT title, archive_path, has_archive FROM bookmarks WHERE id = ?", id).
+			Scan(&title, &archivePath, &hasArchive)
+		if err != nil {
+			return fmt.Errorf("error loading bookmark %d: %v", id, err)
+		}
+		if !hasArchive {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(archivePath, "readable.html"))
+		if err != nil {
+			return fmt.Errorf("error reading archived content for bookmark %d: %v", id, err)
+		}
+		chapters = append(chapters, epubChapter{id: id, title: title, html: string(content)})
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("error creating epub file: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	// mimetype must be the first entry, stored uncompressed.
+	mimeWriter, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return fmt.Errorf("error writing mimetype entry: %v", err)
+	}
+	if _, err := io.WriteString(mimeWriter, "application/epub+zip"); err != nil {
+		return err
+	}
+
+	if err := writeZipEntry(zw, "META-INF/container.xml", epubContainerXML); err != nil {
+		return err
+	}
+	if err := writeZipEntry(zw, "OEBPS/content.opf", epubContentOPF(chapters)); err != nil {
+		return err
+	}
+	if err := writeZipEntry(zw, "OEBPS/toc.ncx", epubTocNCX(chapters)); err != nil {
+		return err
+	}
+	for i, c := range chapters {
+		name := fmt.Sprintf("OEBPS/chapter%d.xhtml", i+1)
+		if err := writeZipEntry(zw, name, epubChapterXHTML(c.title, c.html)); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeZipEntry(zw *zip.Writer, name, content string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %v", name, err)
+	}
+	_, err = io.WriteString(w, content)
+	return err
+}
+
+const epubContainerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+func epubContentOPF(chapters []epubChapter) string {
+	manifest := ""
+	spine := ""
+	for i := range chapters {
+		id := fmt.Sprintf("chapter%d", i+1)
+		manifest += fmt.Sprintf(`    <item id="%s" href="%s.xhtml" media-type="application/xhtml+xml"/>`+"\n", id, id)
+		spine += fmt.Sprintf(`    <itemref idref="%s"/>`+"\n", id)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="2.0" unique-identifier="bookmark-parser-epub">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>Archived Bookmarks</dc:title>
+    <dc:language>en</dc:language>
+    <dc:identifier id="bookmark-parser-epub">bookmark-parser-epub</dc:identifier>
+  </metadata>
+  <manifest>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+%s  </manifest>
+  <spine toc="ncx">
+%s  </spine>
+</package>
+`, manifest, spine)
+}
+
+func epubTocNCX(chapters []epubChapter) string {
+	navPoints := ""
+	for i, c := range chapters {
+		navPoints += fmt.Sprintf(`    <navPoint id="navpoint-%d" playOrder="%d">
+      <navLabel><text>%s</text></navLabel>
+      <content src="chapter%d.xhtml"/>
+    </navPoint>
+`, i+1, i+1, escapeXMLText(c.title), i+1)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head/>
+  <docTitle><text>Archived Bookmarks</text></docTitle>
+  <navMap>
+%s  </navMap>
+</ncx>
+`, navPoints)
+}
+
+func epubChapterXHTML(title, bodyHTML string) string {
+	safeBody, err := xhtmlSafeBody(bodyHTML)
+	if err != nil {
+		// bodyHTML is archived (readability-cleaned) HTML, not raw user
+		// input, so a parse failure here means something upstream already
+		// produced malformed markup. Fall back to escaping it as plain text
+		// rather than emitting an EPUB the reader can't open at all.
+		safeBody = escapeXMLText(bodyHTML)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head><title>%s</title></head>
+<body>
+%s
+</body>
+</html>
+`, escapeXMLText(title), safeBody)
+}
+
+// escapeXMLText escapes the characters that would otherwise break a well
+// formed XML document if they appeared unescaped in text content, e.g. a
+// bookmark title containing "&" or "<".
+func escapeXMLText(s string) string {
+	return strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+	).Replace(s)
+}
+
+var voidElementPattern = regexp.MustCompile(`<(area|base|br|col|embed|hr|img|input|link|meta|source|track|wbr)((?:\s[^<>]*?)?)/?>`)
+
+// xhtmlSafeBody re-serializes bodyHTML (already readability-cleaned HTML
+// produced by html.Render in ArchiveBookmark) through x/net/html so text
+// content is properly escaped, then makes sure every void element like <br>
+// and <img> ends in "/>", since XHTML parsers reject unclosed ones. Void
+// elements html.Render already wrote as "<br/>" are left as they are.
+func xhtmlSafeBody(bodyHTML string) (string, error) {
+	doc, err := html.Parse(strings.NewReader("<html><body>" + bodyHTML + "</body></html>"))
+	if err != nil {
+		return "", err
+	}
+
+	var body *html.Node
+	var find func(*html.Node)
+	find = func(n *html.Node) {
+		if body != nil {
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == "body" {
+			body = n
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			find(c)
+		}
+	}
+	find(doc)
+	if body == nil {
+		return "", fmt.Errorf("no body element found after parsing")
+	}
+
+	var buf bytes.Buffer
+	for c := body.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return "", err
+		}
+	}
+
+	return voidElementPattern.ReplaceAllString(buf.String(), `<$1$2/>`), nil
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// ParseFirefoxPlaces reads bookmarks out of a Firefox places.sqlite profile
+// database. Folder paths are reconstructed by walking the moz_bookmarks parent
+// chain up to one of Firefox's root folders (menu, toolbar, unfiled, tags).
+func ParseFirefoxPlaces(dbPath string) ([]Bookmark, error) {
+	db, err := sql.Open("sqlite3", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("error opening places database: %v", err)
+	}
+	defer db.Close()
+
+	titles, parents, err := loadFirefoxFolders(db)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT b.title, p.url, b.parent
+		FROM moz_bookmarks b
+		JOIN moz_places p ON p.id = b.fk
+		WHERE b.type = 1 AND p.url IS NOT NULL
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying bookmarks: %v", err)
+	}
+	defer rows.Close()
+
+	var bookmarks []Bookmark
+	for rows.Next() {
+		var title sql.NullString
+		var url string
+		var parent int64
+		if err := rows.Scan(&title, &url, &parent); err != nil {
+			return nil, fmt.Errorf("error scanning bookmark row: %v", err)
+		}
+		bookmarks = append(bookmarks, Bookmark{
+			Title:  title.String,
+			URL:    url,
+			Folder: firefoxFolderPath(parent, titles, parents),
+		})
+	}
+
+	return bookmarks, rows.Err()
+}
+
+// loadFirefoxFolders reads every moz_bookmarks folder (type = 2) into id->title
+// and id->parent maps so folder paths can be reconstructed without a query per
+// bookmark.
+func loadFirefoxFolders(db *sql.DB) (titles map[int64]string, parents map[int64]int64, err error) {
+	rows, err := db.Query(`SELECT id, title, parent FROM moz_bookmarks WHERE type = 2`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error querying folders: %v", err)
+	}
+	defer rows.Close()
+
+	titles = make(map[int64]string)
+	parents = make(map[int64]int64)
+	for rows.Next() {
+		var id, parent int64
+		var title sql.NullString
+		if err := rows.Scan(&id, &title, &parent); err != nil {
+			return nil, nil, fmt.Errorf("error scanning folder row: %v", err)
+		}
+		titles[id] = title.String
+		parents[id] = parent
+	}
+
+	return titles, parents, rows.Err()
+}
+
+// firefoxFolderPath walks the parent chain from id up to a root folder,
+// returning the folder names joined with "/" in root-to-leaf order.
+func firefoxFolderPath(id int64, titles map[int64]string, parents map[int64]int64) string {
+	var parts []string
+	for id != 0 {
+		if title := titles[id]; title != "" && title != "places" {
+			parts = append(parts, title)
+		}
+		next, ok := parents[id]
+		if !ok || next == id {
+			break
+		}
+		id = next
+	}
+
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+
+	return strings.Join(parts, "/")
+}
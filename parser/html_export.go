@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+)
+
+// WriteBookmarksHTML writes bookmarks to a Chrome-compatible Netscape
+// bookmark HTML file at outputPath, grouped by folder, with title used for
+// the page's <TITLE>/<H1>. This is the Store-backed counterpart to the
+// *sql.DB-based Export* functions in parser.go, for callers that already have a
+// parser.Store rather than a raw database handle.
+func WriteBookmarksHTML(bookmarks []Bookmark, outputPath, title string) error {
+	bookmarksByFolder := make(map[string][]Bookmark)
+	var folderOrder []string
+	for _, b := range bookmarks {
+		if _, ok := bookmarksByFolder[b.Folder]; !ok {
+			folderOrder = append(folderOrder, b.Folder)
+		}
+		bookmarksByFolder[b.Folder] = append(bookmarksByFolder[b.Folder], b)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("error creating output file: %v", err)
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file.
+     It will be read and overwritten.
+     DO NOT EDIT! -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>%s</TITLE>
+<H1>%s</H1>
+<DL><p>
+`, title, title)
+	if err != nil {
+		return fmt.Errorf("error writing HTML header: %v", err)
+	}
+
+	for _, folder := range folderOrder {
+		if folder != "" {
+			if _, err := fmt.Fprintf(file, "    <DT><H3>%s</H3>\n    <DL><p>\n", folder); err != nil {
+				return fmt.Errorf("error writing folder header: %v", err)
+			}
+		}
+
+		for _, b := range bookmarksByFolder[folder] {
+			if _, err := fmt.Fprintf(file, "        <DT><A HREF=\"%s\">%s</A>\n", b.URL, b.Title); err != nil {
+				return fmt.Errorf("error writing bookmark: %v", err)
+			}
+		}
+
+		if folder != "" {
+			if _, err := file.WriteString("    </DL><p>\n"); err != nil {
+				return fmt.Errorf("error writing folder footer: %v", err)
+			}
+		}
+	}
+
+	if _, err := file.WriteString("</DL><p>\n"); err != nil {
+		return fmt.Errorf("error writing HTML footer: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package parser
+
+import "fmt"
+
+// ImportSource parses bookmarks out of a particular browser or service's export
+// format and returns them in the common Bookmark shape.
+type ImportSource interface {
+	Import(path string) ([]Bookmark, error)
+}
+
+// htmlImportSource handles the Netscape bookmark HTML format used by Chrome and,
+// with its TAGS attribute extension, Pocket.
+type htmlImportSource struct{}
+
+func (htmlImportSource) Import(path string) ([]Bookmark, error) {
+	return ParseFile(path)
+}
+
+// firefoxImportSource reads a Firefox places.sqlite profile database.
+type firefoxImportSource struct{}
+
+func (firefoxImportSource) Import(path string) ([]Bookmark, error) {
+	return ParseFirefoxPlaces(path)
+}
+
+// safariImportSource reads Safari's Bookmarks.plist file.
+type safariImportSource struct{}
+
+func (safariImportSource) Import(path string) ([]Bookmark, error) {
+	return ParseSafariPlist(path)
+}
+
+// NewImportSource returns the ImportSource implementation for format, one of
+// "chrome", "firefox", "safari", or "pocket". An empty format defaults to chrome.
+func NewImportSource(format string) (ImportSource, error) {
+	switch format {
+	case "", "chrome", "pocket":
+		return htmlImportSource{}, nil
+	case "firefox":
+		return firefoxImportSource{}, nil
+	case "safari":
+		return safariImportSource{}, nil
+	default:
+		return nil, fmt.Errorf("unknown import format: %s", format)
+	}
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+//go:embed migrations
+var migrationFS embed.FS
+
+// runMigrations applies every .sql file under migrations/<dialect> whose
+// numeric prefix isn't already recorded in schema_migrations, in filename
+// order. Each file is expected to be idempotent-safe to track (it runs at
+// most once per database), which lets new columns and indexes get rolled out
+// without hand-written ALTER-if-missing checks at every call site.
+func runMigrations(db *sql.DB, dialect string) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY
+		)
+	`); err != nil {
+		return fmt.Errorf("error creating schema_migrations table: %v", err)
+	}
+
+	applied := make(map[int]bool)
+	rows, err := db.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return fmt.Errorf("error reading schema_migrations: %v", err)
+	}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning schema_migrations row: %v", err)
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	dir := "migrations/" + dialect
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return fmt.Errorf("error listing migrations for %s: %v", dialect, err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, entry := range entries {
+		var version int
+		if _, err := fmt.Sscanf(entry.Name(), "%d_", &version); err != nil {
+			continue
+		}
+		if applied[version] {
+			continue
+		}
+
+		content, err := migrationFS.ReadFile(dir + "/" + entry.Name())
+		if err != nil {
+			return fmt.Errorf("error reading migration %s: %v", entry.Name(), err)
+		}
+		if _, err := db.Exec(string(content)); err != nil {
+			return fmt.Errorf("error applying migration %s: %v", entry.Name(), err)
+		}
+		if _, err := db.Exec(recordMigrationQuery(dialect), version); err != nil {
+			return fmt.Errorf("error recording migration %s: %v", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// recordMigrationQuery returns the INSERT used to mark a migration applied,
+// using each dialect's own placeholder syntax ("?" for SQLite/MySQL, "$1" for
+// Postgres).
+func recordMigrationQuery(dialect string) string {
+	if dialect == "postgres" {
+		return "INSERT INTO schema_migrations (version) VALUES ($1)"
+	}
+	return "INSERT INTO schema_migrations (version) VALUES (?)"
+}
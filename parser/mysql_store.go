@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// MySQLStore is the Store implementation backed by MySQL/MariaDB.
+type MySQLStore struct {
+	db *sql.DB
+}
+
+func newMySQLStore(dsn string) (*MySQLStore, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening mysql database: %v", err)
+	}
+	return &MySQLStore{db: db}, nil
+}
+
+func (s *MySQLStore) Init() error { return runMigrations(s.db, "mysql") }
+
+func (s *MySQLStore) Save(bookmarks []Bookmark) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	// Check for duplicates using the canonicalized URL, so trivial variants
+	// (scheme, trailing slash, tracking params, ...) still match.
+	stmt, err := tx.Prepare(`
+		INSERT INTO bookmarks (title, url, canonical_url, folder, tags, is_duplicate, duplicate_of)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, b := range bookmarks {
+		canonical := CanonicalizeURL(b.URL)
+
+		var existingID int64
+		err := tx.QueryRow("SELECT id FROM bookmarks WHERE canonical_url = ?", canonical).Scan(&existingID)
+		isDuplicate := err == nil
+
+		if isDuplicate {
+			// Leave canonical_url unset on duplicate rows: MySQL's UNIQUE
+			// index likewise allows any number of NULLs through.
+			_, err = stmt.Exec(b.Title, b.URL, nil, b.Folder, tagsToColumn(b.Tags), true, existingID)
+		} else {
+			_, err = stmt.Exec(b.Title, b.URL, canonical, b.Folder, tagsToColumn(b.Tags), false, nil)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Search falls back to a LIKE query over title/url, narrowed by the
+// tag/folder filters: MySQL's
+// FULLTEXT indexes don't share SQLite FTS5's MATCH syntax or bm25 ranking, so
+// full parity with SQLiteStore.Search is left for a dedicated follow-up.
+func (s *MySQLStore) Search(query string, opts SearchOptions) ([]SearchResult, error) {
+	return likeSearch(s.db, query, opts)
+}
+
+func (s *MySQLStore) List(filter string) ([]Bookmark, error) {
+	return listBookmarks(s.db, filter)
+}
+
+func (s *MySQLStore) UpdateStatus(id int64, status BookmarkStatus) error {
+	_, err := s.db.Exec(`
+		UPDATE bookmarks
+		SET is_dead = ?, is_redirect = ?, redirect_url = ?,
+		    http_status = ?, final_url = ?, check_attempts = ?, last_checked_at = ?,
+		    content_hash = ?
+		WHERE id = ?
+	`, status.Dead, status.Redirect, status.RedirectURL,
+		status.HTTPStatus, status.FinalURL, status.CheckAttempts, status.LastCheckedAt.Format(time.RFC3339),
+		status.ContentHash, id)
+	return err
+}
+
+func (s *MySQLStore) MarkContentDuplicate(id, canonicalID int64) error {
+	_, err := s.db.Exec(
+		"UPDATE bookmarks SET is_content_duplicate = TRUE, content_duplicate_of = ? WHERE id = ?",
+		canonicalID, id,
+	)
+	return err
+}
+
+func (s *MySQLStore) Iterate(fn func(Bookmark) error) error {
+	return iterateBookmarks(s.db, fn)
+}
+
+func (s *MySQLStore) Close() error { return s.db.Close() }
@@ -18,30 +18,54 @@ type Bookmark struct {
 	Title       string
 	URL         string
 	Folder      string
+	Tags        []string
 	Dead        bool
 	Redirect    bool
 	RedirectURL string
 	Duplicate   bool
 	DuplicateOf int64
+
+	// Fields populated by the Store-backed validator (see validator.go). A
+	// zero LastCheckedAt means the bookmark has never been validated.
+	HTTPStatus    int
+	FinalURL      string
+	CheckAttempts int
+	LastCheckedAt time.Time
+
+	// CanonicalURL is the deduped identity used by SaveBookmarks; see
+	// CanonicalizeURL. ContentHash, ContentDuplicate, and ContentDuplicateOf
+	// mirror the columns the validator fills in from each fetched page's
+	// readable text.
+	CanonicalURL       string
+	ContentHash        string
+	ContentDuplicate   bool
+	ContentDuplicateOf int64
 }
 
-// InitDB creates the bookmarks table if it doesn't exist
+// InitDB brings a SQLite bookmarks database up to date by applying every
+// pending migration under migrations/sqlite. Safe to call on every startup.
 func InitDB(db *sql.DB) error {
-	_, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS bookmarks (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			title TEXT NOT NULL,
-			url TEXT NOT NULL,
-			folder TEXT,
-			is_dead BOOLEAN DEFAULT FALSE,
-			is_redirect BOOLEAN DEFAULT FALSE,
-			redirect_url TEXT,
-			is_duplicate BOOLEAN DEFAULT FALSE,
-			duplicate_of INTEGER,
-			FOREIGN KEY(duplicate_of) REFERENCES bookmarks(id)
-		)
-	`)
-	return err
+	return runMigrations(db, "sqlite")
+}
+
+// tagsToColumn joins tags into the flat comma-separated form stored in the tags column.
+func tagsToColumn(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
+// columnToTags splits the tags column back into individual tag strings.
+func columnToTags(column string) []string {
+	if column == "" {
+		return nil
+	}
+	parts := strings.Split(column, ",")
+	tags := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p != "" {
+			tags = append(tags, p)
+		}
+	}
+	return tags
 }
 
 // ParseFile reads the HTML bookmark file and returns a slice of bookmarks
@@ -77,10 +101,18 @@ func Parse(r io.Reader) ([]Bookmark, error) {
 			case "a":
 				// Extract bookmark
 				var url, title string
+				var tags []string
 				for _, attr := range n.Attr {
-					if attr.Key == "href" {
+					switch attr.Key {
+					case "href":
 						url = attr.Val
-						break
+					case "tags":
+						// Pocket's export stores comma-separated tags in a TAGS attribute.
+						for _, t := range strings.Split(attr.Val, ",") {
+							if t = strings.TrimSpace(t); t != "" {
+								tags = append(tags, t)
+							}
+						}
 					}
 				}
 				if n.FirstChild != nil {
@@ -91,6 +123,7 @@ func Parse(r io.Reader) ([]Bookmark, error) {
 						Title:  strings.TrimSpace(title),
 						URL:    url,
 						Folder: currentFolder,
+						Tags:   tags,
 					})
 				}
 			}
@@ -143,19 +176,9 @@ func SaveBookmarks(db *sql.DB, bookmarks []Bookmark) error {
 		return err
 	}
 
-	// First, check for duplicates
-	urlMap := make(map[string]int64)
-	for _, b := range bookmarks {
-		var existingID int64
-		err := tx.QueryRow("SELECT id FROM bookmarks WHERE url = ?", b.URL).Scan(&existingID)
-		if err == nil {
-			urlMap[b.URL] = existingID
-		}
-	}
-
 	stmt, err := tx.Prepare(`
-		INSERT INTO bookmarks (title, url, folder, is_duplicate, duplicate_of)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO bookmarks (title, url, canonical_url, folder, tags, is_duplicate, duplicate_of)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		tx.Rollback()
@@ -163,15 +186,42 @@ func SaveBookmarks(db *sql.DB, bookmarks []Bookmark) error {
 	}
 	defer stmt.Close()
 
+	// Dedup against canonical_url rather than the raw url, so trivial
+	// variants (scheme, trailing slash, tracking params, ...) still match.
+	// The lookup and insert are interleaved per-row (matching
+	// MySQLStore/PostgresStore) rather than done as one upfront pass, so two
+	// bookmarks in the same batch that canonicalize to the same URL are
+	// caught too: the second one sees the first's freshly inserted row.
+	seen := make(map[string]int64)
 	for _, b := range bookmarks {
-		if duplicateID, exists := urlMap[b.URL]; exists {
-			_, err = stmt.Exec(b.Title, b.URL, b.Folder, true, duplicateID)
-		} else {
-			_, err = stmt.Exec(b.Title, b.URL, b.Folder, false, nil)
+		canonical := CanonicalizeURL(b.URL)
+
+		existingID, isDuplicate := seen[canonical]
+		if !isDuplicate {
+			err := tx.QueryRow("SELECT id FROM bookmarks WHERE canonical_url = ?", canonical).Scan(&existingID)
+			isDuplicate = err == nil
 		}
-		if err != nil {
-			tx.Rollback()
-			return err
+
+		if isDuplicate {
+			// Leave canonical_url unset on duplicate rows: the UNIQUE index
+			// only needs to hold for the one canonical row per URL, and
+			// SQLite treats NULL as distinct from every other NULL.
+			if _, err := stmt.Exec(b.Title, b.URL, nil, b.Folder, tagsToColumn(b.Tags), true, existingID); err != nil {
+				tx.Rollback()
+				return err
+			}
+		} else {
+			res, err := stmt.Exec(b.Title, b.URL, canonical, b.Folder, tagsToColumn(b.Tags), false, nil)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+			id, err := res.LastInsertId()
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+			seen[canonical] = id
 		}
 	}
 
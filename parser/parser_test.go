@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := InitDB(db); err != nil {
+		t.Fatalf("initializing test db: %v", err)
+	}
+	return db
+}
+
+// TestSaveBookmarksSameBatchDuplicate covers a routine case: the same URL
+// bookmarked into two folders, imported together in one batch. Before the
+// lookup/insert were interleaved, neither row existed in the DB yet when
+// urlMap was built, so both inserted with the same non-NULL canonical_url,
+// tripping the UNIQUE index and rolling back the whole import.
+func TestSaveBookmarksSameBatchDuplicate(t *testing.T) {
+	db := newTestDB(t)
+
+	bookmarks := []Bookmark{
+		{Title: "Example", URL: "https://example.com/post", Folder: "Work"},
+		{Title: "Example (again)", URL: "https://example.com/post", Folder: "Personal"},
+	}
+	if err := SaveBookmarks(db, bookmarks); err != nil {
+		t.Fatalf("SaveBookmarks with an in-batch duplicate: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM bookmarks").Scan(&count); err != nil {
+		t.Fatalf("counting bookmarks: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("count = %d, want 2 (both rows should be saved)", count)
+	}
+
+	var dupCount int
+	if err := db.QueryRow("SELECT COUNT(*) FROM bookmarks WHERE is_duplicate = 1").Scan(&dupCount); err != nil {
+		t.Fatalf("counting duplicates: %v", err)
+	}
+	if dupCount != 1 {
+		t.Fatalf("is_duplicate count = %d, want 1", dupCount)
+	}
+
+	var canonicalCount int
+	if err := db.QueryRow("SELECT COUNT(*) FROM bookmarks WHERE canonical_url IS NOT NULL").Scan(&canonicalCount); err != nil {
+		t.Fatalf("counting canonical rows: %v", err)
+	}
+	if canonicalCount != 1 {
+		t.Fatalf("canonical_url-set count = %d, want 1", canonicalCount)
+	}
+}
+
+// TestSaveBookmarksAcrossBatches covers the same dedup path across separate
+// Save calls (e.g. two import runs), which already worked before the
+// in-batch fix and must keep working.
+func TestSaveBookmarksAcrossBatches(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := SaveBookmarks(db, []Bookmark{{Title: "First", URL: "https://example.com/x"}}); err != nil {
+		t.Fatalf("first SaveBookmarks: %v", err)
+	}
+	if err := SaveBookmarks(db, []Bookmark{{Title: "Second", URL: "https://example.com/x/"}}); err != nil {
+		t.Fatalf("second SaveBookmarks: %v", err)
+	}
+
+	var dupCount int
+	if err := db.QueryRow("SELECT COUNT(*) FROM bookmarks WHERE is_duplicate = 1").Scan(&dupCount); err != nil {
+		t.Fatalf("counting duplicates: %v", err)
+	}
+	if dupCount != 1 {
+		t.Fatalf("is_duplicate count = %d, want 1 (trailing slash variant should still dedup)", dupCount)
+	}
+}
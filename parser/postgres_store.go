@@ -0,0 +1,212 @@
+package parser
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgresStore is the Store implementation backed by PostgreSQL. It uses
+// $n placeholders throughout since lib/pq doesn't support "?".
+type PostgresStore struct {
+	db *sql.DB
+}
+
+func newPostgresStore(dsn string) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening postgres database: %v", err)
+	}
+	return &PostgresStore{db: db}, nil
+}
+
+func (s *PostgresStore) Init() error { return runMigrations(s.db, "postgres") }
+
+func (s *PostgresStore) Save(bookmarks []Bookmark) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	// Dedup against canonical_url rather than the raw url, so trivial
+	// variants (scheme, trailing slash, tracking params, ...) still match.
+	// Every bookmark gets its own row (matching SQLiteStore/MySQLStore):
+	// the incoming duplicate is inserted with duplicate_of pointing at the
+	// existing row, which is left untouched.
+	stmt, err := tx.Prepare(`
+		INSERT INTO bookmarks (title, url, canonical_url, folder, tags, is_duplicate, duplicate_of)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, b := range bookmarks {
+		canonical := CanonicalizeURL(b.URL)
+
+		var existingID int64
+		err := tx.QueryRow("SELECT id FROM bookmarks WHERE canonical_url = $1", canonical).Scan(&existingID)
+		isDuplicate := err == nil
+
+		if isDuplicate {
+			// Leave canonical_url unset on duplicate rows: the UNIQUE index
+			// only needs to hold for the one canonical row per URL, and
+			// Postgres treats NULL as distinct from every other NULL.
+			_, err = stmt.Exec(b.Title, b.URL, nil, b.Folder, tagsToColumn(b.Tags), true, existingID)
+		} else {
+			_, err = stmt.Exec(b.Title, b.URL, canonical, b.Folder, tagsToColumn(b.Tags), false, nil)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Search falls back to a LIKE query: Postgres' native full-text search uses
+// tsvector/tsquery, not SQLite's FTS5 MATCH syntax, so full parity with
+// SQLiteStore.Search is left for a dedicated follow-up.
+func (s *PostgresStore) Search(query string, opts SearchOptions) ([]SearchResult, error) {
+	var conditions []string
+	var args []interface{}
+	n := 0
+	next := func() string { n++; return fmt.Sprintf("$%d", n) }
+
+	if query != "" {
+		conditions = append(conditions, fmt.Sprintf("(title ILIKE %s OR url ILIKE %s)", next(), next()))
+		args = append(args, "%"+query+"%", "%"+query+"%")
+	}
+	if opts.Folder != "" {
+		conditions = append(conditions, fmt.Sprintf("folder = %s", next()))
+		args = append(args, opts.Folder)
+	}
+	if opts.Tag != "" {
+		conditions = append(conditions, fmt.Sprintf("(',' || tags || ',') LIKE %s", next()))
+		args = append(args, "%,"+opts.Tag+",%")
+	}
+	if opts.ExcludeTag != "" {
+		conditions = append(conditions, fmt.Sprintf("(',' || tags || ',') NOT LIKE %s", next()))
+		args = append(args, "%,"+opts.ExcludeTag+",%")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf(`
+		SELECT id, title, url, folder, tags, is_dead, is_redirect
+		FROM bookmarks %s ORDER BY id
+	`, where), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching bookmarks: %v", err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		var tags string
+		if err := rows.Scan(&r.ID, &r.Title, &r.URL, &r.Folder, &tags, &r.Dead, &r.Redirect); err != nil {
+			return nil, fmt.Errorf("error scanning search result: %v", err)
+		}
+		r.Tags = columnToTags(tags)
+		results = append(results, r)
+	}
+
+	return results, rows.Err()
+}
+
+func (s *PostgresStore) List(filter string) ([]Bookmark, error) {
+	where := ""
+	switch filter {
+	case "dead":
+		where = "WHERE is_dead = TRUE"
+	case "redirect":
+		where = "WHERE is_redirect = TRUE"
+	case "valid":
+		where = "WHERE is_dead = FALSE AND is_redirect = FALSE"
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf(`
+		SELECT id, title, url, folder, tags, is_dead, is_redirect, redirect_url
+		FROM bookmarks %s ORDER BY folder, title
+	`, where))
+	if err != nil {
+		return nil, fmt.Errorf("error listing bookmarks: %v", err)
+	}
+	defer rows.Close()
+
+	var bookmarks []Bookmark
+	for rows.Next() {
+		var b Bookmark
+		var tags string
+		var redirectURL sql.NullString
+		if err := rows.Scan(&b.ID, &b.Title, &b.URL, &b.Folder, &tags, &b.Dead, &b.Redirect, &redirectURL); err != nil {
+			return nil, fmt.Errorf("error scanning bookmark: %v", err)
+		}
+		b.Tags = columnToTags(tags)
+		b.RedirectURL = redirectURL.String
+		bookmarks = append(bookmarks, b)
+	}
+
+	return bookmarks, rows.Err()
+}
+
+func (s *PostgresStore) UpdateStatus(id int64, status BookmarkStatus) error {
+	_, err := s.db.Exec(`
+		UPDATE bookmarks
+		SET is_dead = $1, is_redirect = $2, redirect_url = $3,
+		    http_status = $4, final_url = $5, check_attempts = $6, last_checked_at = $7,
+		    content_hash = $8
+		WHERE id = $9
+	`, status.Dead, status.Redirect, status.RedirectURL,
+		status.HTTPStatus, status.FinalURL, status.CheckAttempts, status.LastCheckedAt.Format(time.RFC3339),
+		status.ContentHash, id)
+	return err
+}
+
+func (s *PostgresStore) MarkContentDuplicate(id, canonicalID int64) error {
+	_, err := s.db.Exec(
+		"UPDATE bookmarks SET is_content_duplicate = TRUE, content_duplicate_of = $1 WHERE id = $2",
+		canonicalID, id,
+	)
+	return err
+}
+
+func (s *PostgresStore) Iterate(fn func(Bookmark) error) error {
+	rows, err := s.db.Query(`
+		SELECT id, title, url, folder, tags, check_attempts, last_checked_at, content_hash
+		FROM bookmarks
+	`)
+	if err != nil {
+		return fmt.Errorf("error querying bookmarks: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b Bookmark
+		var tags string
+		var lastCheckedAt, contentHash sql.NullString
+		if err := rows.Scan(&b.ID, &b.Title, &b.URL, &b.Folder, &tags, &b.CheckAttempts, &lastCheckedAt, &contentHash); err != nil {
+			return fmt.Errorf("error scanning bookmark: %v", err)
+		}
+		b.Tags = columnToTags(tags)
+		b.LastCheckedAt = parseCheckedAt(lastCheckedAt)
+		b.ContentHash = contentHash.String
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *PostgresStore) Close() error { return s.db.Close() }
@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// listBookmarks returns bookmarks matching filter ("", "valid", "dead", or
+// "redirect"). Shared by stores that use "?" placeholders (SQLite, MySQL).
+func listBookmarks(db *sql.DB, filter string) ([]Bookmark, error) {
+	where := ""
+	switch filter {
+	case "dead":
+		where = "WHERE is_dead = TRUE"
+	case "redirect":
+		where = "WHERE is_redirect = TRUE"
+	case "valid":
+		where = "WHERE is_dead = FALSE AND is_redirect = FALSE"
+	}
+
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT id, title, url, folder, tags, is_dead, is_redirect, redirect_url
+		FROM bookmarks %s ORDER BY folder, title
+	`, where))
+	if err != nil {
+		return nil, fmt.Errorf("error listing bookmarks: %v", err)
+	}
+	defer rows.Close()
+
+	var bookmarks []Bookmark
+	for rows.Next() {
+		var b Bookmark
+		var tags string
+		var redirectURL sql.NullString
+		if err := rows.Scan(&b.ID, &b.Title, &b.URL, &b.Folder, &tags, &b.Dead, &b.Redirect, &redirectURL); err != nil {
+			return nil, fmt.Errorf("error scanning bookmark: %v", err)
+		}
+		b.Tags = columnToTags(tags)
+		b.RedirectURL = redirectURL.String
+		bookmarks = append(bookmarks, b)
+	}
+
+	return bookmarks, rows.Err()
+}
+
+// iterateBookmarks streams every bookmark row to fn, letting callers (e.g.
+// the validator) process the database without holding every row in memory.
+func iterateBookmarks(db *sql.DB, fn func(Bookmark) error) error {
+	rows, err := db.Query(`
+		SELECT id, title, url, folder, tags, check_attempts, last_checked_at, content_hash
+		FROM bookmarks
+	`)
+	if err != nil {
+		return fmt.Errorf("error querying bookmarks: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b Bookmark
+		var tags string
+		var lastCheckedAt, contentHash sql.NullString
+		if err := rows.Scan(&b.ID, &b.Title, &b.URL, &b.Folder, &tags, &b.CheckAttempts, &lastCheckedAt, &contentHash); err != nil {
+			return fmt.Errorf("error scanning bookmark: %v", err)
+		}
+		b.Tags = columnToTags(tags)
+		b.LastCheckedAt = parseCheckedAt(lastCheckedAt)
+		b.ContentHash = contentHash.String
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// parseCheckedAt parses the RFC3339 string stored in last_checked_at,
+// returning the zero time for bookmarks that have never been validated.
+func parseCheckedAt(s sql.NullString) time.Time {
+	if !s.Valid || s.String == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// likeSearch is the portable fallback search used by stores without a
+// SQLite-FTS5-equivalent full-text index: a LIKE match over title/url,
+// narrowed by the same tag/folder filters as SQLiteStore's FTS5 search.
+// Shared by stores that use "?" placeholders (SQLite, MySQL).
+func likeSearch(db *sql.DB, query string, opts SearchOptions) ([]SearchResult, error) {
+	var conditions []string
+	var args []interface{}
+
+	if query != "" {
+		conditions = append(conditions, "(title LIKE ? OR url LIKE ?)")
+		args = append(args, "%"+query+"%", "%"+query+"%")
+	}
+	if opts.Folder != "" {
+		conditions = append(conditions, "folder = ?")
+		args = append(args, opts.Folder)
+	}
+	if opts.Tag != "" {
+		conditions = append(conditions, "(',' || tags || ',') LIKE ?")
+		args = append(args, "%,"+opts.Tag+",%")
+	}
+	if opts.ExcludeTag != "" {
+		conditions = append(conditions, "(',' || tags || ',') NOT LIKE ?")
+		args = append(args, "%,"+opts.ExcludeTag+",%")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT id, title, url, folder, tags, is_dead, is_redirect
+		FROM bookmarks %s ORDER BY id
+	`, where), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching bookmarks: %v", err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		var tags string
+		if err := rows.Scan(&r.ID, &r.Title, &r.URL, &r.Folder, &tags, &r.Dead, &r.Redirect); err != nil {
+			return nil, fmt.Errorf("error scanning search result: %v", err)
+		}
+		r.Tags = columnToTags(tags)
+		results = append(results, r)
+	}
+
+	return results, rows.Err()
+}
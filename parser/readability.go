@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// candidateScore accumulates a content score for a single node while scanning
+// the document, mirroring the core heuristics of Arc90's readability
+// algorithm.
+type candidateScore struct {
+	node  *html.Node
+	score float64
+}
+
+var readabilityPositiveHints = []string{"article", "content", "post", "entry", "main", "body"}
+var readabilityNegativeHints = []string{"comment", "sidebar", "footer", "nav", "menu", "ad", "popup", "widget"}
+
+// extractReadableContent picks the subtree of doc most likely to be the main
+// article body. It scores <p>, <div>, and <article> nodes by text length,
+// comma count, and class/id hints, then returns the highest-scoring node's
+// parent, keeping only the children whose score clears a threshold relative
+// to the winner.
+func extractReadableContent(doc *html.Node) *html.Node {
+	var candidates []*candidateScore
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "p", "div", "article":
+				if score := scoreNode(n); score > 0 {
+					candidates = append(candidates, &candidateScore{node: n, score: score})
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if len(candidates) == 0 {
+		return doc
+	}
+
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.score > best.score {
+			best = c
+		}
+	}
+
+	container := &html.Node{Type: html.ElementNode, Data: "div"}
+	threshold := best.score * 0.2
+	parent := best.node.Parent
+	if parent == nil {
+		container.AppendChild(cloneNode(best.node))
+		return container
+	}
+
+	for sib := parent.FirstChild; sib != nil; sib = sib.NextSibling {
+		if sib == best.node || scoreNode(sib) >= threshold {
+			container.AppendChild(cloneNode(sib))
+		}
+	}
+
+	return container
+}
+
+// scoreNode rates how likely n is to be part of the main article: one point
+// per 100 characters of text (capped), one per comma, and class/id hints that
+// suggest article content score positively while hints suggesting
+// boilerplate (sidebar, footer, comments, ...) score negatively.
+func scoreNode(n *html.Node) float64 {
+	text := textContent(n)
+	if len(text) < 25 {
+		return 0
+	}
+
+	lengthScore := float64(len(text)) / 100
+	if lengthScore > 3 {
+		lengthScore = 3
+	}
+	score := 1 + lengthScore + float64(strings.Count(text, ","))
+
+	hint := strings.ToLower(attrValue(n, "class") + " " + attrValue(n, "id"))
+	for _, p := range readabilityPositiveHints {
+		if strings.Contains(hint, p) {
+			score += 25
+		}
+	}
+	for _, neg := range readabilityNegativeHints {
+		if strings.Contains(hint, neg) {
+			score -= 25
+		}
+	}
+
+	return score
+}
+
+func attrValue(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// textContent concatenates all text node descendants of n.
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+func cloneNode(n *html.Node) *html.Node {
+	clone := &html.Node{
+		Type: n.Type,
+		Data: n.Data,
+		Attr: append([]html.Attribute(nil), n.Attr...),
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		clone.AppendChild(cloneNode(c))
+	}
+	return clone
+}
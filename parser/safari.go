@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+
+	"howett.net/plist"
+)
+
+// safariBookmark mirrors the subset of Safari's Bookmarks.plist schema needed
+// to reconstruct bookmarks and their containing folder path.
+type safariBookmark struct {
+	Title           string                 `plist:"Title"`
+	WebBookmarkType string                 `plist:"WebBookmarkType"`
+	URLString       string                 `plist:"URLString"`
+	URIDictionary   map[string]interface{} `plist:"URIDictionary"`
+	Children        []safariBookmark       `plist:"Children"`
+}
+
+// ParseSafariPlist reads bookmarks out of Safari's Bookmarks.plist file.
+func ParseSafariPlist(path string) ([]Bookmark, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening plist file: %v", err)
+	}
+	defer f.Close()
+
+	var root safariBookmark
+	if err := plist.NewDecoder(f).Decode(&root); err != nil {
+		return nil, fmt.Errorf("error decoding plist: %v", err)
+	}
+
+	var bookmarks []Bookmark
+	walkSafariBookmarks(root, "", &bookmarks)
+	return bookmarks, nil
+}
+
+// walkSafariBookmarks recurses through the Children tree, collecting leaf
+// bookmarks ("WebBookmarkTypeLeaf" nodes) and tracking the folder path built
+// up from "WebBookmarkTypeList" folder titles.
+func walkSafariBookmarks(node safariBookmark, folder string, bookmarks *[]Bookmark) {
+	if node.WebBookmarkType == "WebBookmarkTypeLeaf" {
+		title := node.Title
+		if title == "" {
+			if t, ok := node.URIDictionary["title"].(string); ok {
+				title = t
+			}
+		}
+		if node.URLString != "" && title != "" {
+			*bookmarks = append(*bookmarks, Bookmark{
+				Title:  title,
+				URL:    node.URLString,
+				Folder: folder,
+			})
+		}
+		return
+	}
+
+	childFolder := folder
+	if node.Title != "" {
+		if childFolder != "" {
+			childFolder += "/" + node.Title
+		} else {
+			childFolder = node.Title
+		}
+	}
+	for _, child := range node.Children {
+		walkSafariBookmarks(child, childFolder, bookmarks)
+	}
+}
@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// SearchResult is a single bookmark match from a full-text search, along with a
+// snippet highlighting where the query matched.
+type SearchResult struct {
+	Bookmark
+	Snippet string
+}
+
+// SearchOptions narrows a full-text search beyond the free-text query string.
+type SearchOptions struct {
+	Tag        string
+	Folder     string
+	ExcludeTag string
+}
+
+// SearchBookmarks runs query against the bookmarks_fts virtual table using
+// SQLite's FTS5 MATCH syntax (phrase queries, prefix "foo*", boolean
+// AND/OR/NOT), ranked by bm25 relevance, and returns matches with
+// snippet-highlighted excerpts. Tag, Folder, and ExcludeTag in opts are applied
+// as additional filters alongside the query.
+func SearchBookmarks(db *sql.DB, query string, opts SearchOptions) ([]SearchResult, error) {
+	var conditions []string
+	var args []interface{}
+
+	if query != "" {
+		conditions = append(conditions, "bookmarks_fts MATCH ?")
+		args = append(args, query)
+	}
+	if opts.Folder != "" {
+		conditions = append(conditions, "bookmarks_fts.folder = ?")
+		args = append(args, opts.Folder)
+	}
+	if opts.Tag != "" {
+		conditions = append(conditions, "(',' || bookmarks_fts.tags || ',') LIKE ?")
+		args = append(args, "%,"+opts.Tag+",%")
+	}
+	if opts.ExcludeTag != "" {
+		conditions = append(conditions, "(',' || bookmarks_fts.tags || ',') NOT LIKE ?")
+		args = append(args, "%,"+opts.ExcludeTag+",%")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	orderBy := "b.id"
+	snippetExpr := "NULL"
+	if query != "" {
+		orderBy = "bm25(bookmarks_fts)"
+		snippetExpr = "snippet(bookmarks_fts, -1, '[', ']', '...', 10)"
+	}
+
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT b.id, b.title, b.url, b.folder, b.tags, b.is_dead, b.is_redirect,
+			%s
+		FROM bookmarks_fts
+		JOIN bookmarks b ON b.id = bookmarks_fts.rowid
+		%s
+		ORDER BY %s
+	`, snippetExpr, where, orderBy), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching bookmarks: %v", err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		var tags string
+		var snippet sql.NullString
+		if err := rows.Scan(&r.ID, &r.Title, &r.URL, &r.Folder, &tags, &r.Dead, &r.Redirect, &snippet); err != nil {
+			return nil, fmt.Errorf("error scanning search result: %v", err)
+		}
+		r.Tags = columnToTags(tags)
+		r.Snippet = snippet.String
+		results = append(results, r)
+	}
+
+	return results, rows.Err()
+}
@@ -0,0 +1,80 @@
+package parser
+
+import "testing"
+
+// TestSearchBookmarksQueryless covers folder/tag/exclude-tag filters used
+// with no free-text query (main.go's search subcommand, and cmd/server's
+// GET /api/bookmarks on page load, both permit this). With no MATCH query the
+// snippet column is SQL NULL, which used to be scanned straight into a string
+// and error on every queryless search.
+func TestSearchBookmarksQueryless(t *testing.T) {
+	db := newTestDB(t)
+
+	bookmarks := []Bookmark{
+		{Title: "Go Concurrency", URL: "https://example.com/go", Folder: "Dev", Tags: []string{"go", "concurrency"}},
+		{Title: "Recipe Box", URL: "https://example.com/food", Folder: "Home", Tags: []string{"cooking"}},
+	}
+	if err := SaveBookmarks(db, bookmarks); err != nil {
+		t.Fatalf("SaveBookmarks: %v", err)
+	}
+
+	results, err := SearchBookmarks(db, "", SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchBookmarks with no query errored: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if r.Snippet != "" {
+			t.Errorf("Snippet = %q, want empty when there's no MATCH query", r.Snippet)
+		}
+	}
+
+	results, err = SearchBookmarks(db, "", SearchOptions{Folder: "Dev"})
+	if err != nil {
+		t.Fatalf("SearchBookmarks with -folder and no query errored: %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Go Concurrency" {
+		t.Fatalf("folder filter returned %+v, want just the Dev bookmark", results)
+	}
+
+	results, err = SearchBookmarks(db, "", SearchOptions{Tag: "cooking"})
+	if err != nil {
+		t.Fatalf("SearchBookmarks with -tag and no query errored: %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Recipe Box" {
+		t.Fatalf("tag filter returned %+v, want just the Recipe Box bookmark", results)
+	}
+
+	results, err = SearchBookmarks(db, "", SearchOptions{ExcludeTag: "cooking"})
+	if err != nil {
+		t.Fatalf("SearchBookmarks with -exclude-tag and no query errored: %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Go Concurrency" {
+		t.Fatalf("exclude-tag filter returned %+v, want just the Go Concurrency bookmark", results)
+	}
+}
+
+// TestSearchBookmarksWithQuery covers the ordinary free-text path, where
+// snippet() does return a non-NULL highlighted excerpt.
+func TestSearchBookmarksWithQuery(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := SaveBookmarks(db, []Bookmark{
+		{Title: "Go Concurrency Patterns", URL: "https://example.com/go", Folder: "Dev"},
+	}); err != nil {
+		t.Fatalf("SaveBookmarks: %v", err)
+	}
+
+	results, err := SearchBookmarks(db, "concurrency", SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchBookmarks: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if results[0].Snippet == "" {
+		t.Error("Snippet is empty, want a highlighted excerpt for a matched query")
+	}
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteStore is the Store implementation backed by a local SQLite file. It
+// delegates to the package-level functions, which already target SQLite's
+// dialect (AUTOINCREMENT, the bookmarks_fts virtual table, etc.).
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+func newSQLiteStore(dsn string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening sqlite database: %v", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// NewSQLiteStore wraps an already-open *sql.DB as a Store, for callers like
+// cmd/server that hold a connection opened outside of NewStore and need
+// Store-backed operations (e.g. ValidateAndUpdateStore) on top of it.
+func NewSQLiteStore(db *sql.DB) Store {
+	return &SQLiteStore{db: db}
+}
+
+func (s *SQLiteStore) Init() error { return runMigrations(s.db, "sqlite") }
+
+func (s *SQLiteStore) Save(bookmarks []Bookmark) error { return SaveBookmarks(s.db, bookmarks) }
+
+func (s *SQLiteStore) Search(query string, opts SearchOptions) ([]SearchResult, error) {
+	return SearchBookmarks(s.db, query, opts)
+}
+
+func (s *SQLiteStore) List(filter string) ([]Bookmark, error) {
+	return listBookmarks(s.db, filter)
+}
+
+func (s *SQLiteStore) UpdateStatus(id int64, status BookmarkStatus) error {
+	_, err := s.db.Exec(`
+		UPDATE bookmarks
+		SET is_dead = ?, is_redirect = ?, redirect_url = ?,
+		    http_status = ?, final_url = ?, check_attempts = ?, last_checked_at = ?,
+		    content_hash = ?
+		WHERE id = ?
+	`, status.Dead, status.Redirect, status.RedirectURL,
+		status.HTTPStatus, status.FinalURL, status.CheckAttempts, status.LastCheckedAt.Format(time.RFC3339),
+		status.ContentHash, id)
+	return err
+}
+
+func (s *SQLiteStore) MarkContentDuplicate(id, canonicalID int64) error {
+	_, err := s.db.Exec(
+		"UPDATE bookmarks SET is_content_duplicate = TRUE, content_duplicate_of = ? WHERE id = ?",
+		canonicalID, id,
+	)
+	return err
+}
+
+func (s *SQLiteStore) Iterate(fn func(Bookmark) error) error {
+	return iterateBookmarks(s.db, fn)
+}
+
+func (s *SQLiteStore) Close() error { return s.db.Close() }
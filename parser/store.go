@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"fmt"
+	"time"
+)
+
+// BookmarkStatus carries the result of validating a single bookmark's URL.
+// LastCheckedAt is the zero time until the bookmark has been validated once.
+type BookmarkStatus struct {
+	Dead          bool
+	Redirect      bool
+	RedirectURL   string
+	HTTPStatus    int
+	FinalURL      string
+	CheckAttempts int
+	LastCheckedAt time.Time
+	// ContentHash is the SHA-256 of the bookmark's extracted article text,
+	// empty if the validator couldn't fetch or extract it this run.
+	ContentHash string
+}
+
+// Store is the storage backend used by the CLI: one implementation per
+// supported database (SQLiteStore, MySQLStore, PostgresStore). Dialect
+// differences (BOOLEAN vs TINYINT, AUTOINCREMENT vs SERIAL/AUTO_INCREMENT,
+// upsert syntax, ...) live inside each implementation and its
+// migrations/<dialect> files rather than in caller code.
+type Store interface {
+	// Init applies any pending schema migrations.
+	Init() error
+	// Save inserts bookmarks, flagging duplicates against what's already stored.
+	Save(bookmarks []Bookmark) error
+	// Search runs a free-text query with optional tag/folder filters.
+	Search(query string, opts SearchOptions) ([]SearchResult, error)
+	// List returns bookmarks matching filter ("", "valid", "dead", or "redirect").
+	List(filter string) ([]Bookmark, error)
+	// UpdateStatus records the outcome of validating a bookmark's URL.
+	UpdateStatus(id int64, status BookmarkStatus) error
+	// MarkContentDuplicate flags id as a content-level duplicate of canonicalID.
+	MarkContentDuplicate(id, canonicalID int64) error
+	// Iterate streams every bookmark to fn without loading them all into memory.
+	Iterate(fn func(Bookmark) error) error
+	// Close releases the underlying database connection.
+	Close() error
+}
+
+// NewStore opens a Store for driver ("sqlite", "mysql", or "postgres") using
+// dsn as the connection string. An empty driver defaults to sqlite.
+func NewStore(driver, dsn string) (Store, error) {
+	switch driver {
+	case "", "sqlite", "sqlite3":
+		return newSQLiteStore(dsn)
+	case "mysql":
+		return newMySQLStore(dsn)
+	case "postgres", "postgresql":
+		return newPostgresStore(dsn)
+	default:
+		return nil, fmt.Errorf("unknown database driver: %s", driver)
+	}
+}
@@ -0,0 +1,409 @@
+package parser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// maxContentHashBytes caps how much of a response body the validator reads
+// before extracting readable content to hash, so one oversized page can't
+// blow up memory use during a validation run.
+const maxContentHashBytes = 2 << 20 // 2MB
+
+// ValidateOptions configures ValidateAndUpdateStore's worker pool.
+type ValidateOptions struct {
+	// Resume skips bookmarks last checked more recently than MaxAge ago.
+	Resume bool
+	MaxAge time.Duration
+
+	// Timeout bounds the whole validation run; zero means no deadline.
+	Timeout time.Duration
+
+	// Concurrency caps the number of bookmarks checked at once, across all hosts.
+	Concurrency int
+	// PerHostConcurrency caps in-flight requests to a single host.
+	PerHostConcurrency int
+	// RequestsPerSecond caps the request rate to a single host; zero defaults to 1.
+	RequestsPerSecond float64
+}
+
+func (o ValidateOptions) withDefaults() ValidateOptions {
+	if o.Concurrency <= 0 {
+		o.Concurrency = 20
+	}
+	if o.PerHostConcurrency <= 0 {
+		o.PerHostConcurrency = 2
+	}
+	if o.RequestsPerSecond <= 0 {
+		o.RequestsPerSecond = 1
+	}
+	return o
+}
+
+// ValidateAndUpdateStore checks every bookmark in store, recording its
+// dead/redirect status along with http_status/final_url/check_attempts/
+// last_checked_at. It replaces the single long transaction of the original
+// ValidateAndUpdateBookmarks (which held the SQLite database locked across
+// every HTTP call) with a read pass followed by a concurrent worker pool
+// that writes each result back as its own short UpdateStatus call, so a
+// run can be interrupted without losing the work it already did.
+//
+// Checks are grouped per-host, both to cap concurrency per host and to
+// throttle the request rate, so validating thousands of bookmarks doesn't
+// hammer any one site. With opts.Resume set, only bookmarks whose
+// LastCheckedAt is older than opts.MaxAge (or that have never been
+// checked) are re-validated.
+func ValidateAndUpdateStore(store Store, opts ValidateOptions) error {
+	opts = opts.withDefaults()
+
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	cutoff := time.Now().Add(-opts.MaxAge)
+	var targets []Bookmark
+	err := store.Iterate(func(b Bookmark) error {
+		if opts.Resume && !b.LastCheckedAt.IsZero() && b.LastCheckedAt.After(cutoff) {
+			return nil
+		}
+		targets = append(targets, b)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Println("No bookmarks need checking")
+		return nil
+	}
+
+	limiter := newHostLimiter(opts.PerHostConcurrency, opts.RequestsPerSecond)
+	client := &http.Client{Timeout: 15 * time.Second}
+
+	type validationResult struct {
+		id     int64
+		status BookmarkStatus
+		err    error
+	}
+
+	jobs := make(chan Bookmark)
+	results := make(chan validationResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range jobs {
+				status, err := validateOne(ctx, client, limiter, b)
+				results <- validationResult{id: b.ID, status: status, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, b := range targets {
+			select {
+			case jobs <- b:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var stats struct{ total, dead, redirects int }
+	checked := 0
+	for r := range results {
+		checked++
+		if r.err != nil {
+			fmt.Printf("[%d/%d] error checking bookmark %d: %v\n", checked, len(targets), r.id, r.err)
+			continue
+		}
+
+		if err := store.UpdateStatus(r.id, r.status); err != nil {
+			return fmt.Errorf("error recording validation result for bookmark %d: %v", r.id, err)
+		}
+
+		stats.total++
+		switch {
+		case r.status.Dead:
+			stats.dead++
+		case r.status.Redirect:
+			stats.redirects++
+		}
+		fmt.Printf("[%d/%d] bookmark %d: status=%d dead=%v redirect=%v\n",
+			checked, len(targets), r.id, r.status.HTTPStatus, r.status.Dead, r.status.Redirect)
+	}
+
+	fmt.Printf("Checked %d bookmarks: %d dead, %d redirects\n", stats.total, stats.dead, stats.redirects)
+
+	if err := markContentDuplicates(store); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// markContentDuplicates groups bookmarks by ContentHash and flags every
+// bookmark but the earliest (lowest ID) in each group as a content
+// duplicate of it. This catches mirrors and moved pages that redirect to
+// distinct URLs and so aren't caught by CanonicalizeURL-based dedup.
+func markContentDuplicates(store Store) error {
+	hashGroups := make(map[string][]int64)
+	err := store.Iterate(func(b Bookmark) error {
+		if b.ContentHash != "" {
+			hashGroups[b.ContentHash] = append(hashGroups[b.ContentHash], b.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error grouping bookmarks by content hash: %v", err)
+	}
+
+	duplicates := 0
+	for _, ids := range hashGroups {
+		if len(ids) < 2 {
+			continue
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		canonical := ids[0]
+		for _, id := range ids[1:] {
+			if err := store.MarkContentDuplicate(id, canonical); err != nil {
+				return fmt.Errorf("error marking bookmark %d as a content duplicate: %v", id, err)
+			}
+			duplicates++
+		}
+	}
+	if duplicates > 0 {
+		fmt.Printf("Found %d content-duplicate bookmarks\n", duplicates)
+	}
+
+	return nil
+}
+
+// validateOne fetches a single bookmark's URL and returns the resulting status.
+func validateOne(ctx context.Context, client *http.Client, limiter *hostLimiter, b Bookmark) (BookmarkStatus, error) {
+	u, err := url.Parse(b.URL)
+	if err != nil {
+		return BookmarkStatus{}, fmt.Errorf("error parsing url: %v", err)
+	}
+
+	release, err := limiter.acquire(ctx, u.Hostname())
+	if err != nil {
+		return BookmarkStatus{}, err
+	}
+
+	status, finalURL, attempts, fetchErr := fetchWithRetry(ctx, client, b.URL)
+	release()
+
+	result := BookmarkStatus{
+		HTTPStatus:    status,
+		FinalURL:      finalURL,
+		CheckAttempts: attempts,
+		LastCheckedAt: time.Now(),
+	}
+	if fetchErr != nil {
+		result.Dead = true
+		return result, nil
+	}
+	if status >= 400 {
+		result.Dead = true
+	}
+	if finalURL != "" && finalURL != b.URL {
+		result.Redirect = true
+		result.RedirectURL = finalURL
+	}
+
+	if !result.Dead {
+		hashURL := finalURL
+		if hashURL == "" {
+			hashURL = b.URL
+		}
+		if hash, err := fetchContentHash(ctx, client, limiter, u.Hostname(), hashURL); err == nil {
+			result.ContentHash = hash
+		}
+	}
+
+	return result, nil
+}
+
+// fetchContentHash GETs rawURL, extracts its main article text with the same
+// readability heuristics used by the archive subcommand, and returns the
+// SHA-256 of that text so content-level duplicates (mirrors, moved pages
+// that redirect to distinct URLs) can be matched regardless of boilerplate
+// differences between pages.
+func fetchContentHash(ctx context.Context, client *http.Client, limiter *hostLimiter, host, rawURL string) (string, error) {
+	release, err := limiter.acquire(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	doc, err := html.Parse(io.LimitReader(resp.Body, maxContentHashBytes))
+	if err != nil {
+		return "", fmt.Errorf("error parsing response body: %v", err)
+	}
+
+	text := textContent(extractReadableContent(doc))
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// fetchWithRetry tries HEAD first, falling back to GET only if the server
+// rejects HEAD (405/501); that fallback request counts as one of the
+// attempts. 5xx responses and network errors are retried up to 3 attempts
+// total, waiting 1s and then 2s between them, each plus up to the same again
+// in jitter.
+func fetchWithRetry(ctx context.Context, client *http.Client, rawURL string) (status int, finalURL string, attempts int, err error) {
+	const maxAttempts = 3
+	method := http.MethodHead
+	backoff := time.Second
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		attempts = attempt
+		status, finalURL, err = doRequest(ctx, client, method, rawURL)
+
+		if err == nil {
+			if method == http.MethodHead && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+				method = http.MethodGet
+				continue
+			}
+			if status < 500 {
+				return status, finalURL, attempts, nil
+			}
+		}
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		jitter := time.Duration(rand.Int63n(int64(backoff)))
+		select {
+		case <-time.After(backoff + jitter):
+		case <-ctx.Done():
+			return status, finalURL, attempts, ctx.Err()
+		}
+		backoff *= 2
+	}
+
+	return status, finalURL, attempts, err
+}
+
+// doRequest performs a single HTTP request, following redirects, and reports
+// the final status code and URL reached.
+func doRequest(ctx context.Context, client *http.Client, method, rawURL string) (status int, finalURL string, err error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.Request != nil && resp.Request.URL != nil {
+		finalURL = resp.Request.URL.String()
+	}
+	return resp.StatusCode, finalURL, nil
+}
+
+// hostLimiter caps concurrency and request rate per hostname, so validating
+// many bookmarks on the same site doesn't hammer it.
+type hostLimiter struct {
+	mu          sync.Mutex
+	sem         map[string]chan struct{}
+	nextAllowed map[string]time.Time
+	concurrency int
+	interval    time.Duration
+}
+
+func newHostLimiter(concurrency int, requestsPerSecond float64) *hostLimiter {
+	var interval time.Duration
+	if requestsPerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return &hostLimiter{
+		sem:         make(map[string]chan struct{}),
+		nextAllowed: make(map[string]time.Time),
+		concurrency: concurrency,
+		interval:    interval,
+	}
+}
+
+// acquire blocks until a concurrency slot and rate-limit slot are both
+// available for host, returning a function that releases the slot.
+func (h *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	h.mu.Lock()
+	sem, ok := h.sem[host]
+	if !ok {
+		sem = make(chan struct{}, h.concurrency)
+		h.sem[host] = sem
+	}
+	h.mu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if h.interval > 0 {
+		h.mu.Lock()
+		now := time.Now()
+		next := h.nextAllowed[host]
+		if next.Before(now) {
+			next = now
+		}
+		wait := next.Sub(now)
+		h.nextAllowed[host] = next.Add(h.interval)
+		h.mu.Unlock()
+
+		if wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				<-sem
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	return func() { <-sem }, nil
+}
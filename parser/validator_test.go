@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHostLimiterEnforcesRate(t *testing.T) {
+	limiter := newHostLimiter(1, 10) // 10 req/s => ~100ms between requests
+	ctx := context.Background()
+
+	const requests = 3
+	start := time.Now()
+	for i := 0; i < requests; i++ {
+		release, err := limiter.acquire(ctx, "example.com")
+		if err != nil {
+			t.Fatalf("acquire %d: %v", i, err)
+		}
+		release()
+	}
+	elapsed := time.Since(start)
+
+	// 3 requests at 10/s must take at least 2 intervals (~200ms); the
+	// pre-fix zero-time bug let every call through with no wait at all.
+	if min := 150 * time.Millisecond; elapsed < min {
+		t.Errorf("3 requests at 10/s took %v, want at least %v", elapsed, min)
+	}
+}
+
+func TestHostLimiterPerHostIndependence(t *testing.T) {
+	limiter := newHostLimiter(1, 5) // 5 req/s => ~200ms between requests per host
+
+	start := time.Now()
+	releaseA, err := limiter.acquire(context.Background(), "a.example.com")
+	if err != nil {
+		t.Fatalf("acquire a: %v", err)
+	}
+	releaseA()
+
+	releaseB, err := limiter.acquire(context.Background(), "b.example.com")
+	if err != nil {
+		t.Fatalf("acquire b: %v", err)
+	}
+	releaseB()
+	elapsed := time.Since(start)
+
+	if max := 100 * time.Millisecond; elapsed > max {
+		t.Errorf("two different hosts took %v, want under %v (rate limits shouldn't cross hosts)", elapsed, max)
+	}
+}
+
+func TestHostLimiterRespectsContextCancellation(t *testing.T) {
+	limiter := newHostLimiter(1, 0)
+
+	release, err := limiter.acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := limiter.acquire(ctx, "example.com"); err == nil {
+		t.Error("acquire with a cancelled context and a full concurrency slot should return an error")
+	}
+}
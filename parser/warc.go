@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// writeWARCRecord writes a "request" record followed by a "response" record
+// for req/resp to path, capturing the HTTP headers and body as seen on the
+// wire.
+func writeWARCRecord(path string, req *http.Request, resp *http.Response, body []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if err := writeWARCRequest(w, req); err != nil {
+		return err
+	}
+	if err := writeWARCResponse(w, resp, body); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+func writeWARCRequest(w *bufio.Writer, req *http.Request) error {
+	var header bytes.Buffer
+	fmt.Fprintf(&header, "%s %s HTTP/1.1\r\n", req.Method, req.URL.RequestURI())
+	fmt.Fprintf(&header, "Host: %s\r\n", req.URL.Host)
+	req.Header.Write(&header)
+	header.WriteString("\r\n")
+
+	return writeWARCEntry(w, "request", req.URL.String(), header.Bytes())
+}
+
+func writeWARCResponse(w *bufio.Writer, resp *http.Response, body []byte) error {
+	var header bytes.Buffer
+	fmt.Fprintf(&header, "HTTP/%d.%d %s\r\n", resp.ProtoMajor, resp.ProtoMinor, resp.Status)
+	resp.Header.Write(&header)
+	header.WriteString("\r\n")
+
+	content := append(header.Bytes(), body...)
+	return writeWARCEntry(w, "response", resp.Request.URL.String(), content)
+}
+
+func writeWARCEntry(w *bufio.Writer, recordType, targetURI string, content []byte) error {
+	fmt.Fprintf(w, "WARC/1.1\r\n")
+	fmt.Fprintf(w, "WARC-Type: %s\r\n", recordType)
+	fmt.Fprintf(w, "WARC-Target-URI: %s\r\n", targetURI)
+	fmt.Fprintf(w, "WARC-Date: %s\r\n", time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "Content-Type: application/http; msgtype=%s\r\n", recordType)
+	fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(content))
+	if _, err := w.Write(content); err != nil {
+		return err
+	}
+	_, err := w.WriteString("\r\n\r\n")
+	return err
+}